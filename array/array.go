@@ -0,0 +1,105 @@
+// Package array implements Array, a sparse index from int32 positions
+// to uint32 values: it stores an entry only for the positions actually
+// present, instead of allocating a dense slice sized to the largest one.
+package array
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Array maps a strictly ascending set of int32 indexes to uint32 values.
+type Array struct {
+	indexes []int32
+	elts    []uint32
+}
+
+// New builds an Array mapping each indexes[i] to elts[i]. indexes must
+// be strictly ascending.
+func New(indexes []int32, elts []uint32) (*Array, error) {
+
+	if len(indexes) != len(elts) {
+		return nil, fmt.Errorf("array: indexes and elts must have the same length, got %d and %d", len(indexes), len(elts))
+	}
+
+	for i := 1; i < len(indexes); i++ {
+		if indexes[i] <= indexes[i-1] {
+			return nil, fmt.Errorf("array: indexes must be strictly ascending, got %d after %d", indexes[i], indexes[i-1])
+		}
+	}
+
+	a := &Array{
+		indexes: make([]int32, len(indexes)),
+		elts:    make([]uint32, len(elts)),
+	}
+	copy(a.indexes, indexes)
+	copy(a.elts, elts)
+
+	return a, nil
+}
+
+// Get returns the value at i and whether i is present.
+func (a *Array) Get(i int32) (uint32, bool) {
+
+	j := sort.Search(len(a.indexes), func(j int) bool { return a.indexes[j] >= i })
+	if j >= len(a.indexes) || a.indexes[j] != i {
+		return 0, false
+	}
+
+	return a.elts[j], true
+}
+
+// MarshalBinary serializes the array to a portable byte form.
+func (a *Array) MarshalBinary() ([]byte, error) {
+
+	n := len(a.indexes)
+	buf := make([]byte, 4, 4+n*8)
+
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(n))
+
+	for _, idx := range a.indexes {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], uint32(idx))
+		buf = append(buf, b[:]...)
+	}
+	for _, e := range a.elts {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], e)
+		buf = append(buf, b[:]...)
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary restores an Array written by MarshalBinary.
+func (a *Array) UnmarshalBinary(b []byte) error {
+
+	if len(b) < 4 {
+		return fmt.Errorf("array: truncated header")
+	}
+
+	n := int(binary.LittleEndian.Uint32(b[0:4]))
+	need := 4 + n*4 + n*4
+	if len(b) < need {
+		return fmt.Errorf("array: truncated body")
+	}
+
+	indexes := make([]int32, n)
+	elts := make([]uint32, n)
+
+	off := 4
+	for i := 0; i < n; i++ {
+		indexes[i] = int32(binary.LittleEndian.Uint32(b[off : off+4]))
+		off += 4
+	}
+	for i := 0; i < n; i++ {
+		elts[i] = binary.LittleEndian.Uint32(b[off : off+4])
+		off += 4
+	}
+
+	a.indexes = indexes
+	a.elts = elts
+
+	return nil
+}
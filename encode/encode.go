@@ -0,0 +1,103 @@
+// Package encode defines the fixed-width value encodings SlimTrie uses
+// to store a leaf's value inline, and a self-describing tag so a
+// marshaled SlimTrie can pick the right one back up without the caller
+// repeating itself at Unmarshal time.
+package encode
+
+import "fmt"
+
+// Encoder defines how SlimTrie serializes and deserializes a value for
+// storage in, and retrieval from, its leaves.
+type Encoder interface {
+	// Tag identifies this Encoder in a marshaled SlimTrie's header, so
+	// UnmarshalBinary can recover the right Encoder without the caller
+	// having to supply one.
+	Tag() byte
+
+	// Size returns the fixed encoded size in bytes of Encode's output.
+	Size() int
+
+	// Encode appends the encoded bytes of v to buf and returns the result.
+	Encode(buf []byte, v interface{}) []byte
+
+	// Decode reads one value starting at b[0] and returns it along with
+	// the number of bytes consumed.
+	Decode(b []byte) (interface{}, int)
+}
+
+// I32 encodes int32 values in 4 bytes, little-endian.
+type I32 struct{}
+
+// Tag implements Encoder.
+func (I32) Tag() byte { return 1 }
+
+// Size implements Encoder.
+func (I32) Size() int { return 4 }
+
+// Encode implements Encoder.
+func (I32) Encode(buf []byte, v interface{}) []byte {
+	n := uint32(v.(int32))
+	return append(buf, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+}
+
+// Decode implements Encoder.
+func (I32) Decode(b []byte) (interface{}, int) {
+	n := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+	return int32(n), 4
+}
+
+// U16 encodes uint16 values in 2 bytes, little-endian.
+type U16 struct{}
+
+// Tag implements Encoder.
+func (U16) Tag() byte { return 2 }
+
+// Size implements Encoder.
+func (U16) Size() int { return 2 }
+
+// Encode implements Encoder.
+func (U16) Encode(buf []byte, v interface{}) []byte {
+	n := v.(uint16)
+	return append(buf, byte(n), byte(n>>8))
+}
+
+// Decode implements Encoder.
+func (U16) Decode(b []byte) (interface{}, int) {
+	return uint16(b[0]) | uint16(b[1])<<8, 2
+}
+
+// U32 encodes uint32 values in 4 bytes, little-endian.
+type U32 struct{}
+
+// Tag implements Encoder.
+func (U32) Tag() byte { return 3 }
+
+// Size implements Encoder.
+func (U32) Size() int { return 4 }
+
+// Encode implements Encoder.
+func (U32) Encode(buf []byte, v interface{}) []byte {
+	n := v.(uint32)
+	return append(buf, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+}
+
+// Decode implements Encoder.
+func (U32) Decode(b []byte) (interface{}, int) {
+	n := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+	return n, 4
+}
+
+// FromTag returns the Encoder identified by tag, as written by that
+// Encoder's Tag method.
+func FromTag(tag byte) (Encoder, error) {
+	switch tag {
+	case I32{}.Tag():
+		return I32{}, nil
+	case U16{}.Tag():
+		return U16{}, nil
+	case U32{}.Tag():
+		return U32{}, nil
+	default:
+		return nil, fmt.Errorf("encode: unknown encoder tag %d", tag)
+	}
+}
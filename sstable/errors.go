@@ -0,0 +1,11 @@
+package sstable
+
+import "errors"
+
+var (
+	errFooterSize   = errors.New("sstable: truncated footer")
+	errBadMagic     = errors.New("sstable: bad magic, not an sstable file")
+	errOutOfOrder   = errors.New("sstable: keys must be written in strictly ascending order")
+	errWriterDone   = errors.New("sstable: Finish already called")
+	errFileTooSmall = errors.New("sstable: file smaller than a footer")
+)
@@ -0,0 +1,175 @@
+package sstable
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/openacid/slim/array"
+	"github.com/openacid/slim/trie"
+)
+
+// Reader provides point and range lookups over an sstable file mmap'd
+// read-only. Values are read in place from the mapping; the key index
+// and the key->offset array are decoded with UnmarshalBinary into
+// in-memory copies when the file is opened, not read in place.
+type Reader struct {
+	f    *os.File
+	data []byte
+
+	valueRegion []byte
+
+	st  *trie.SlimTrie
+	arr *array.Array
+
+	keyCount int
+}
+
+// Open mmaps path and parses its footer and key index.
+func Open(path string) (*Reader, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if st.Size() < footerSize {
+		f.Close()
+		return nil, errFileTooSmall
+	}
+
+	data, err := unix.Mmap(int(f.Fd()), 0, int(st.Size()), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	r, err := newReader(f, data)
+	if err != nil {
+		unix.Munmap(data)
+		f.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func newReader(f *os.File, data []byte) (*Reader, error) {
+
+	ft, err := unmarshalFooter(data[len(data)-footerSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	valueRegion := data[:ft.ValueRegionLen]
+	indexRegion := data[ft.ValueRegionLen : ft.ValueRegionLen+ft.IndexRegionLen]
+
+	stLen, n1 := binary.Uvarint(indexRegion)
+	arrLen, n2 := binary.Uvarint(indexRegion[n1:])
+
+	stBytes := indexRegion[n1+n2 : n1+n2+int(stLen)]
+	arrBytes := indexRegion[n1+n2+int(stLen) : n1+n2+int(stLen)+int(arrLen)]
+
+	st := &trie.SlimTrie{}
+	if err := st.UnmarshalBinary(stBytes); err != nil {
+		return nil, err
+	}
+
+	arr := &array.Array{}
+	if err := arr.UnmarshalBinary(arrBytes); err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		f:           f,
+		data:        data,
+		valueRegion: valueRegion,
+		st:          st,
+		arr:         arr,
+		keyCount:    int(ft.KeyCount),
+	}, nil
+}
+
+// Close unmaps the file and closes its descriptor.
+func (r *Reader) Close() error {
+
+	if err := unix.Munmap(r.data); err != nil {
+		return err
+	}
+
+	return r.f.Close()
+}
+
+// Get returns the value for key, and whether it was found. The returned
+// slice aliases the reader's mmap and is valid until Close.
+func (r *Reader) Get(key string) ([]byte, bool) {
+
+	pos, found := r.st.GetI32(key)
+	if !found {
+		return nil, false
+	}
+
+	offset, ok := r.arr.Get(pos)
+	if !ok {
+		return nil, false
+	}
+
+	gotKey, val := r.recordAt(uint32(offset))
+	if !bytes.Equal(gotKey, []byte(key)) {
+		// GetI32 only returns a position for a stored key; this
+		// comparison confirms that against the key actually stored in
+		// the record at that offset.
+		return nil, false
+	}
+
+	return val, true
+}
+
+// Scan returns up to limit (key, value) pairs in [start, end). start
+// need not be an exact stored key: Range resolves it to the first
+// index whose key is >= start, so a sorted scan works from any lower
+// bound, not just one that happens to land on a present key.
+func (r *Reader) Scan(start, end string, limit int) (keys []string, values [][]byte) {
+
+	startIdx, endIdx := r.st.Range(start, end, limit)
+	if int(endIdx) > r.keyCount {
+		endIdx = int32(r.keyCount)
+	}
+
+	for i := startIdx; i < endIdx; i++ {
+		offset, ok := r.arr.Get(i)
+		if !ok {
+			break
+		}
+
+		k, v := r.recordAt(uint32(offset))
+
+		keys = append(keys, string(k))
+		values = append(values, v)
+	}
+
+	return keys, values
+}
+
+// recordAt reads the length-prefixed (key, value) record starting at
+// offset in the value region.
+func (r *Reader) recordAt(offset uint32) (key, value []byte) {
+
+	buf := r.valueRegion[offset:]
+
+	keyLen, n1 := binary.Uvarint(buf)
+	valLen, n2 := binary.Uvarint(buf[n1:])
+
+	key = buf[n1+n2 : n1+n2+int(keyLen)]
+	value = buf[n1+n2+int(keyLen) : n1+n2+int(keyLen)+int(valLen)]
+
+	return key, value
+}
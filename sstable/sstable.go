@@ -0,0 +1,75 @@
+// Package sstable implements a sorted, immutable key/value file backed
+// by array.Array and trie.SlimTrie, in the spirit of LevelDB's SSTable:
+// a value-block region of length-prefixed (key, value) records, a
+// SlimTrie-indexed key->offset map for point and range lookups, and a
+// trailing footer with a magic number, a version and region lengths.
+//
+// The on-disk layout is:
+//
+//	+----------------------+
+//	| value block          |  length-prefixed (key, value) records,
+//	|                      |  in ascending key order
+//	+----------------------+
+//	| index block          |  SlimTrie (key -> record position) ||
+//	|                      |  array.Array (record position -> offset)
+//	+----------------------+
+//	| footer (fixed size)  |  magic, version, region lengths, key count
+//	+----------------------+
+//
+// A Reader mmaps the whole file and hands the index block's bytes to
+// trie.SlimTrie.UnmarshalBinary and array.Array.UnmarshalBinary, which
+// decode the trie and the offset array into in-memory copies; only the
+// value block is read in place from the mapping.
+package sstable
+
+import "encoding/binary"
+
+const magic uint64 = 0x736c696d2d737374 // "slim-sst"
+
+const version uint32 = 1
+
+// footer is written verbatim, in this field order, at the end of the
+// file.
+type footer struct {
+	Magic          uint64
+	Version        uint32
+	ValueRegionLen uint64
+	IndexRegionLen uint64
+	KeyCount       uint64
+}
+
+const footerSize = 8 + 4 + 8 + 8 + 8
+
+func (f *footer) marshal() []byte {
+
+	b := make([]byte, footerSize)
+
+	binary.LittleEndian.PutUint64(b[0:8], f.Magic)
+	binary.LittleEndian.PutUint32(b[8:12], f.Version)
+	binary.LittleEndian.PutUint64(b[12:20], f.ValueRegionLen)
+	binary.LittleEndian.PutUint64(b[20:28], f.IndexRegionLen)
+	binary.LittleEndian.PutUint64(b[28:36], f.KeyCount)
+
+	return b
+}
+
+func unmarshalFooter(b []byte) (*footer, error) {
+
+	if len(b) != footerSize {
+		return nil, errFooterSize
+	}
+
+	f := &footer{
+		Magic:          binary.LittleEndian.Uint64(b[0:8]),
+		Version:        binary.LittleEndian.Uint32(b[8:12]),
+		ValueRegionLen: binary.LittleEndian.Uint64(b[12:20]),
+		IndexRegionLen: binary.LittleEndian.Uint64(b[20:28]),
+		KeyCount:       binary.LittleEndian.Uint64(b[28:36]),
+	}
+
+	if f.Magic != magic {
+		return nil, errBadMagic
+	}
+
+	return f, nil
+}
@@ -0,0 +1,140 @@
+package sstable
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/openacid/slim/array"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+)
+
+// Writer builds an sstable file from a stream of sorted (key, value)
+// pairs. Put must be called with strictly ascending keys, the same
+// contract trie.NewSlimTrie has on its keys argument; Finish writes the
+// key index and footer and must be called exactly once, after the last
+// Put.
+type Writer struct {
+	w io.Writer
+
+	offset  uint64
+	keys    []string
+	offsets []uint32
+
+	done bool
+}
+
+// NewWriter returns a Writer that appends the sstable's bytes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Put appends a (key, value) record to the value block. Keys must be
+// supplied in ascending order.
+func (w *Writer) Put(key, value []byte) error {
+
+	if w.done {
+		return errWriterDone
+	}
+
+	if len(w.keys) > 0 && string(key) <= w.keys[len(w.keys)-1] {
+		return errOutOfOrder
+	}
+
+	recOffset := w.offset
+
+	var hdr [2 * binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(hdr[:], uint64(len(key)))
+	n += binary.PutUvarint(hdr[n:], uint64(len(value)))
+
+	for _, b := range [][]byte{hdr[:n], key, value} {
+		nw, err := w.w.Write(b)
+		if err != nil {
+			return err
+		}
+		w.offset += uint64(nw)
+	}
+
+	w.keys = append(w.keys, string(key))
+	w.offsets = append(w.offsets, uint32(recOffset))
+
+	return nil
+}
+
+// Finish writes the SlimTrie-indexed key->offset map and the footer,
+// completing the file. The Writer must not be used afterwards.
+func (w *Writer) Finish() error {
+
+	if w.done {
+		return errWriterDone
+	}
+	w.done = true
+
+	valueRegionLen := w.offset
+
+	positions := make([]int32, len(w.keys))
+	for i := range positions {
+		positions[i] = int32(i)
+	}
+
+	st, err := trie.NewSlimTrie(encode.I32{}, w.keys, positions)
+	if err != nil {
+		return err
+	}
+
+	arr, err := array.New(positions, w.offsets)
+	if err != nil {
+		return err
+	}
+
+	stBytes, err := st.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	arrBytes, err := arr.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	indexRegionLen, err := w.writeIndexRegion(stBytes, arrBytes)
+	if err != nil {
+		return err
+	}
+
+	f := &footer{
+		Magic:          magic,
+		Version:        version,
+		ValueRegionLen: valueRegionLen,
+		IndexRegionLen: indexRegionLen,
+		KeyCount:       uint64(len(w.keys)),
+	}
+
+	if _, err := w.w.Write(f.marshal()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeIndexRegion writes stBytes and arrBytes each prefixed with their
+// length, so a Reader can split the index region back into the two
+// without needing either to self-describe its own size.
+func (w *Writer) writeIndexRegion(stBytes, arrBytes []byte) (uint64, error) {
+
+	var lens [2 * binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lens[:], uint64(len(stBytes)))
+	n += binary.PutUvarint(lens[n:], uint64(len(arrBytes)))
+
+	start := w.offset
+
+	for _, b := range [][]byte{lens[:n], stBytes, arrBytes} {
+		nw, err := w.w.Write(b)
+		if err != nil {
+			return 0, err
+		}
+		w.offset += uint64(nw)
+	}
+
+	return w.offset - start, nil
+}
@@ -31,6 +31,12 @@ type SearchResult struct {
 	KeyLen                int
 	ExistingKeyNsPerOp    int64
 	NonexistentKeyNsPerOp int64
+
+	BytesPerOp  int64 `tw-title:"B/op"`
+	AllocsPerOp int64 `tw-title:"allocs/op"`
+	P50NsPerOp  int64 `tw-title:"p50"`
+	P95NsPerOp  int64 `tw-title:"p95"`
+	P99NsPerOp  int64 `tw-title:"p99"`
 }
 
 // GetResult represent the ns/Get() for various key count and several predefined
@@ -49,6 +55,15 @@ type MSABResult struct {
 	Slim     int `tw-title:"SlimTrie"`
 	Array    int `tw-title:"array"`
 	Btree    int `tw-title:"Btree"`
+
+	// The following columns profile the SlimTrie Get() call only: it is
+	// the one this package exists to measure, and a Zipf workload makes
+	// its tail latency, not just its mean, the interesting number.
+	SlimBytesPerOp  int64 `tw-title:"SlimTrie-B/op"`
+	SlimAllocsPerOp int64 `tw-title:"SlimTrie-allocs/op"`
+	SlimP50NsPerOp  int64 `tw-title:"SlimTrie-p50"`
+	SlimP95NsPerOp  int64 `tw-title:"SlimTrie-p95"`
+	SlimP99NsPerOp  int64 `tw-title:"SlimTrie-p99"`
 }
 
 // FPRResult represent the false positive rate.
@@ -200,6 +215,7 @@ func GetMapSlimArrayBtree(keyCounts []int, workload string) []MSABResult {
 	for _, n := range keyCounts {
 
 		mp := benchGet_map_slim_array_btree(NewGetSetting(n, 64), "present", workload)
+		sl := slimLatency(NewGetSetting(n, 64), workload)
 
 		r := MSABResult{
 			KeyCount: n,
@@ -207,6 +223,12 @@ func GetMapSlimArrayBtree(keyCounts []int, workload string) []MSABResult {
 			Slim:     mp["slim"],
 			Array:    mp["array"],
 			Btree:    mp["btree"],
+
+			SlimBytesPerOp:  sl.BytesPerOp,
+			SlimAllocsPerOp: sl.AllocsPerOp,
+			SlimP50NsPerOp:  sl.P50NsPerOp,
+			SlimP95NsPerOp:  sl.P95NsPerOp,
+			SlimP99NsPerOp:  sl.P99NsPerOp,
 		}
 
 		rst = append(rst, r)
@@ -372,6 +394,14 @@ type slimKV struct {
 	slim *trie.SlimTrie
 	// full key-values
 	Elts []*KVElt
+	// optional per-leaf fingerprint, nil unless set by FPRvsMem
+	fp *trie.Fingerprints
+	// direct is true once s.slim has been through Insert (see
+	// mutate.go; Delete keeps s's mode): such a slim stores each value straight in its
+	// SlimTrie node, rather than a position into Elts, since a freshly
+	// inserted key has no stable Elts slot to occupy without an O(n)
+	// copy.
+	direct bool
 }
 
 func (s *slimKV) Get(key string) int32 {
@@ -380,6 +410,16 @@ func (s *slimKV) Get(key string) int32 {
 		return -1
 	}
 
+	if s.direct {
+		return idx
+	}
+
+	if !s.fp.Check(idx, key) {
+		// A fingerprint miss already proves key is absent: skip the
+		// Elts[idx].Key comparison.
+		return -1
+	}
+
 	elt := s.Elts[idx]
 	if elt.Key != key {
 		return -1
@@ -388,6 +428,19 @@ func (s *slimKV) Get(key string) int32 {
 	return elt.Val
 }
 
+// probeFingerprint reports whether key survives down to the
+// fingerprint check alone, bypassing the exact Elts[idx].Key comparison
+// Get always applies: it is the raw signal s.fp's width contributes on
+// its own, used by measureFPR to show the fingerprint/memory tradeoff
+// the exact backstop in Get would otherwise always hide.
+func (s *slimKV) probeFingerprint(key string) bool {
+	idx, found := s.slim.ProbeI32(key)
+	if !found {
+		return false
+	}
+	return s.fp.Check(idx, key)
+}
+
 func maxMask(n int) int {
 	mask := 1
 	for ; (mask<<1 | 1) <= n; mask = mask<<1 | 1 {
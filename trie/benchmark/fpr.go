@@ -0,0 +1,128 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/openacid/low/size"
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/encode"
+	"github.com/openacid/slim/trie"
+)
+
+// FPRvsMemResult shows one point on the fingerprint-width tradeoff
+// curve: bytes/key of the SlimTrie plus its fingerprint, the resulting measured
+// false-positive rate, and the ns/op cost of Get for present and absent
+// keys with that fingerprint enabled.
+type FPRvsMemResult struct {
+	FingerprintBits    int     `tw-title:"fp-bits"`
+	BytesPerKey        int64   `tw-title:"bytes/key"`
+	FPR                float64 `tw-title:"fpr" tw-fmt:"%.4f%%"`
+	ExistingNsPerOp    int64   `tw-title:"present-ns/op"`
+	NonexistentNsPerOp int64   `tw-title:"absent-ns/op"`
+}
+
+// FPRvsMem sweeps fingerprint width for a fixed key count and reports,
+// for each width, where it lands on the memory/FPR/latency tradeoff.
+func FPRvsMem(keyCount int, fingerprintBitsList []int) []FPRvsMemResult {
+
+	keyLen := 64
+
+	keys := benchhelper.RandSortedStrings(keyCount, keyLen, nil)
+	vals := make([]int32, keyCount)
+	for i := range vals {
+		vals[i] = int32(i)
+	}
+	elts := makeKVElts(keys, vals)
+
+	present := make(map[string]bool, keyCount)
+	for _, k := range keys {
+		present[k] = true
+	}
+
+	var rst = make([]FPRvsMemResult, 0, len(fingerprintBitsList))
+
+	for _, bits := range fingerprintBitsList {
+
+		st, fp, err := trie.NewSlimTrieOpts(encode.I32{}, keys, vals, trie.Options{FingerprintBits: bits})
+		if err != nil {
+			panic(err)
+		}
+
+		sk := &slimKV{Elts: elts, slim: st, fp: fp}
+
+		bytesPerKey := (size.Of(st) + size.Of(fp)) / int64(keyCount)
+		fpr := measureFPR(sk, keyLen, present)
+		existingNs, absentNs := measureFPRLatency(sk, keys, keyLen, present)
+
+		rst = append(rst, FPRvsMemResult{
+			FingerprintBits:    bits,
+			BytesPerKey:        bytesPerKey,
+			FPR:                fpr,
+			ExistingNsPerOp:    existingNs,
+			NonexistentNsPerOp: absentNs,
+		})
+	}
+
+	return rst
+}
+
+// measureFPR counts how often an absent key survives down to the
+// fingerprint alone. sk.Get always runs the exact Elts[idx].Key
+// comparison on every hit, which would reject every one of these
+// absent keys regardless of fingerprint width and report a flat 0%; to
+// see the fingerprint's own discriminating power, probe through
+// SlimTrie.ProbeI32 (which, unlike GetI32, narrows any key not past the
+// last stored one down to a candidate leaf) plus the fingerprint check,
+// stopping there.
+func measureFPR(sk *slimKV, keyLen int, present map[string]bool) float64 {
+
+	const r = 100
+	nAbsent := len(sk.Elts) * r
+
+	fp := 0
+	for i := 0; i < nAbsent; {
+		k := benchhelper.RandString(keyLen, nil)
+		if present[k] {
+			continue
+		}
+
+		if sk.probeFingerprint(k) {
+			fp++
+		}
+		i++
+	}
+
+	return float64(fp) / float64(nAbsent)
+}
+
+func measureFPRLatency(sk *slimKV, keys []string, keyLen int, present map[string]bool) (existingNs, absentNs int64) {
+
+	absentKeys := make([]string, 0, 1000)
+	for len(absentKeys) < 1000 {
+		k := benchhelper.RandString(keyLen, nil)
+		if present[k] {
+			continue
+		}
+		absentKeys = append(absentKeys, k)
+	}
+
+	n := len(keys)
+	rst := testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = sk.Get(keys[i%n])
+			}
+		})
+	existingNs = rst.NsPerOp()
+
+	na := len(absentKeys)
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = sk.Get(absentKeys[i%na])
+			}
+		})
+	absentNs = rst.NsPerOp()
+
+	return existingNs, absentNs
+}
@@ -0,0 +1,65 @@
+package benchmark
+
+import "sort"
+
+// latencyHistogram is a log-linear latency histogram in the spirit of
+// HdrHistogram: each power-of-two decade of nanoseconds is split into
+// the same number of linear sub-buckets, so it keeps good relative
+// precision for both sub-microsecond hits and the long tail a Zipf
+// workload produces, without an array sized for the worst case.
+type latencyHistogram struct {
+	subBuckets int
+	counts     map[int]int
+	n          int
+}
+
+func newLatencyHistogram(subBuckets int) *latencyHistogram {
+	return &latencyHistogram{subBuckets: subBuckets, counts: map[int]int{}}
+}
+
+func (h *latencyHistogram) Record(ns int64) {
+
+	if ns < 1 {
+		ns = 1
+	}
+
+	decade := 0
+	for v := ns; v >= 2; v >>= 1 {
+		decade++
+	}
+
+	lo := int64(1) << uint(decade)
+	sub := int((ns - lo) * int64(h.subBuckets) / lo)
+
+	h.counts[decade*h.subBuckets+sub]++
+	h.n++
+}
+
+// Percentile returns the ns value at percentile p, p in [0, 1].
+func (h *latencyHistogram) Percentile(p float64) int64 {
+
+	if h.n == 0 {
+		return 0
+	}
+
+	buckets := make([]int, 0, len(h.counts))
+	for b := range h.counts {
+		buckets = append(buckets, b)
+	}
+	sort.Ints(buckets)
+
+	target := int(p * float64(h.n))
+
+	cum := 0
+	for _, b := range buckets {
+		cum += h.counts[b]
+		if cum >= target {
+			decade := b / h.subBuckets
+			sub := b % h.subBuckets
+			lo := int64(1) << uint(decade)
+			return lo + int64(sub)*lo/int64(h.subBuckets)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,257 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/openacid/low/size"
+	"github.com/openacid/slim/trie"
+)
+
+// Insert returns a new slimKV containing key/val in addition to every
+// entry already in s, leaving s untouched. It delegates directly to
+// SlimTrie.Insert, which copies only the O(depth) nodes on the path to
+// key and shares every other node with s.slim -- unlike the bulk
+// GetSetting path, where a key's stored value is its position in Elts,
+// an incrementally inserted key has no stable Elts slot to occupy
+// without an O(n) copy, so Insert instead stores val directly in the
+// SlimTrie node and marks the result direct so Get knows to read it back
+// that way.
+func (s *slimKV) Insert(key string, val int32) (*slimKV, error) {
+
+	newSlim, err := s.slim.Insert(key, val)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slimKV{slim: newSlim, fp: s.fp, direct: true}, nil
+}
+
+// Delete returns a new slimKV with key removed, leaving s untouched.
+// Deleting an absent key is not an error: it returns a slimKV equivalent
+// to s. Delete never needs to touch Elts -- a surviving key's position
+// in it, if s is in Elts-indexed mode, is unaffected by removing some
+// other key's SlimTrie node -- so it preserves s's storage mode as-is.
+func (s *slimKV) Delete(key string) (*slimKV, error) {
+
+	newSlim, err := s.slim.Delete(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slimKV{slim: newSlim, Elts: s.Elts, fp: s.fp, direct: s.direct}, nil
+}
+
+// InsertResult shows the ns/op cost of a single incremental insert for
+// SlimTrie (via slimKV.Insert), Btree and Map.
+type InsertResult struct {
+	KeyCount int `tw-title:"key-count"`
+	Map      int `tw-title:"map"`
+	Slim     int `tw-title:"SlimTrie"`
+	Btree    int `tw-title:"Btree"`
+}
+
+// DeleteResult shows the ns/op cost of a single tombstone for SlimTrie
+// (via slimKV.Delete), Btree and Map.
+type DeleteResult struct {
+	KeyCount int `tw-title:"key-count"`
+	Map      int `tw-title:"map"`
+	Slim     int `tw-title:"SlimTrie"`
+	Btree    int `tw-title:"Btree"`
+}
+
+// InsertMemResult reports the memory cost of keeping every intermediate
+// version reachable across nInserts incremental inserts, versus keeping
+// the same number of independent from-scratch copies that share nothing
+// with each other -- the actual payoff of SlimTrie.Insert's structural
+// sharing, in bytes per key retained.
+type InsertMemResult struct {
+	KeyCount       int `tw-title:"key-count"`
+	NInserts       int `tw-title:"n-inserts"`
+	IncrementalMem int `tw-title:"incremental-bytes/key"`
+	FromScratchMem int `tw-title:"from-scratch-bytes/key"`
+}
+
+// GetInsertMapSlimBtree compares per-op insert cost of SlimTrie, Map and
+// Btree for several key counts.
+func GetInsertMapSlimBtree(keyCounts []int) []InsertResult {
+
+	var rst = make([]InsertResult, 0, len(keyCounts))
+
+	for _, n := range keyCounts {
+
+		gst := NewGetSetting(n, 64)
+		mp := benchInsert_map_slim_btree(gst)
+
+		rst = append(rst, InsertResult{
+			KeyCount: n,
+			Map:      mp["map"],
+			Slim:     mp["slim"],
+			Btree:    mp["btree"],
+		})
+	}
+
+	return rst
+}
+
+func benchInsert_map_slim_btree(gst *GetSetting) map[string]int {
+
+	nsops := make(map[string]int)
+
+	rst := testing.Benchmark(
+		func(b *testing.B) {
+			sk := gst.SlimKV
+			for i := 0; i < b.N; i++ {
+				nsk, err := sk.Insert(gst.AbsentKeys[i%len(gst.AbsentKeys)], int32(i))
+				if err != nil {
+					panic(err)
+				}
+				sk = nsk
+			}
+		})
+	nsops["slim"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			m := gst.Map
+			for i := 0; i < b.N; i++ {
+				m[gst.AbsentKeys[i%len(gst.AbsentKeys)]] = int32(i)
+			}
+		})
+	nsops["map"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			bt := gst.Btree
+			for i := 0; i < b.N; i++ {
+				k := gst.AbsentKeys[i%len(gst.AbsentKeys)]
+				bt.ReplaceOrInsert(&KVElt{Key: k, Val: int32(i)})
+			}
+		})
+	nsops["btree"] = int(rst.NsPerOp())
+
+	return nsops
+}
+
+// GetDeleteMapSlimBtree compares per-op delete cost of SlimTrie, Map and
+// Btree for several key counts.
+func GetDeleteMapSlimBtree(keyCounts []int) []DeleteResult {
+
+	var rst = make([]DeleteResult, 0, len(keyCounts))
+
+	for _, n := range keyCounts {
+
+		gst := NewGetSetting(n, 64)
+		mp := benchDelete_map_slim_btree(gst)
+
+		rst = append(rst, DeleteResult{
+			KeyCount: n,
+			Map:      mp["map"],
+			Slim:     mp["slim"],
+			Btree:    mp["btree"],
+		})
+	}
+
+	return rst
+}
+
+func benchDelete_map_slim_btree(gst *GetSetting) map[string]int {
+
+	nsops := make(map[string]int)
+
+	rst := testing.Benchmark(
+		func(b *testing.B) {
+			sk := gst.SlimKV
+			for i := 0; i < b.N; i++ {
+				nsk, err := sk.Delete(gst.Keys[i%len(gst.Keys)])
+				if err != nil {
+					panic(err)
+				}
+				sk = nsk
+			}
+		})
+	nsops["slim"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			m := gst.Map
+			for i := 0; i < b.N; i++ {
+				delete(m, gst.Keys[i%len(gst.Keys)])
+			}
+		})
+	nsops["map"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			bt := gst.Btree
+			for i := 0; i < b.N; i++ {
+				bt.Delete(&KVElt{Key: gst.Keys[i%len(gst.Keys)]})
+			}
+		})
+	nsops["btree"] = int(rst.NsPerOp())
+
+	return nsops
+}
+
+// GetInsertMem measures the memory-amplification of retaining every
+// intermediate SlimTrie produced by nInserts incremental inserts,
+// versus retaining the same number of independent from-scratch builds
+// of those same revisions. A single finished revision looks the same
+// size either way -- the payoff of Insert's node sharing only shows up
+// once more than one revision needs to stay reachable at a time, e.g. to
+// serve reads against an older snapshot while newer writes land.
+func GetInsertMem(keyCounts []int, nInserts int) []InsertMemResult {
+
+	var rst = make([]InsertMemResult, 0, len(keyCounts))
+
+	for _, n := range keyCounts {
+
+		gst := NewGetSetting(n, 64)
+
+		versions := make([]*slimKV, 0, nInserts+1)
+		sk := gst.SlimKV
+		versions = append(versions, sk)
+
+		for i := 0; i < nInserts; i++ {
+			nsk, err := sk.Insert(gst.AbsentKeys[i%len(gst.AbsentKeys)], int32(i))
+			if err != nil {
+				panic(err)
+			}
+			versions = append(versions, nsk)
+			sk = nsk
+		}
+
+		slims := make([]*trie.SlimTrie, len(versions))
+		for i, v := range versions {
+			slims[i] = v.slim
+		}
+
+		// The nInserts+1 trees, reachable together: sharing between them
+		// (size.Of is pointer-aware, so a node visited via two different
+		// roots is only counted once) is what Insert's path copying buys.
+		// Measuring just the trees, rather than the whole *slimKV values,
+		// keeps this comparable to independent below -- neither side
+		// pays for Elts or fp here.
+		retained := int(size.Of(slims))
+
+		// The same trees, had each one been a fully independent
+		// from-scratch build sharing nothing with its neighbors.
+		independent := 0
+		for _, st := range slims {
+			independent += int(size.Of(st))
+		}
+
+		finalCount := len(sk.Elts)
+		if finalCount == 0 {
+			finalCount = n
+		}
+
+		rst = append(rst, InsertMemResult{
+			KeyCount:       n,
+			NInserts:       nInserts,
+			IncrementalMem: retained / finalCount,
+			FromScratchMem: independent / finalCount,
+		})
+	}
+
+	return rst
+}
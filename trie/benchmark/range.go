@@ -0,0 +1,147 @@
+package benchmark
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/btree"
+)
+
+// RangeResult shows the ns/op cost of scanning `Width` keys starting from
+// a random key, for Map, SlimTrie, sorted-slice and Btree.
+type RangeResult struct {
+	KeyCount int `tw-title:"key-count"`
+	Width    int `tw-title:"width"`
+	Map      int `tw-title:"map"`
+	Slim     int `tw-title:"SlimTrie"`
+	Array    int `tw-title:"array"`
+	Btree    int `tw-title:"Btree"`
+}
+
+// GetRangeMapSlimArrayBtree compares range-scan cost of SlimTrie, Map,
+// a sorted slice and Btree, for several key counts and range widths.
+func GetRangeMapSlimArrayBtree(keyCounts []int, widths []int) []RangeResult {
+
+	var rst = make([]RangeResult, 0, len(keyCounts)*len(widths))
+
+	for _, n := range keyCounts {
+
+		gst := NewGetSetting(n, 64)
+
+		for _, w := range widths {
+
+			mp := benchRange_map_slim_array_btree(gst, w)
+
+			r := RangeResult{
+				KeyCount: n,
+				Width:    w,
+				Map:      mp["map"],
+				Slim:     mp["slim"],
+				Array:    mp["array"],
+				Btree:    mp["btree"],
+			}
+
+			rst = append(rst, r)
+		}
+	}
+
+	return rst
+}
+
+func benchRange_map_slim_array_btree(gst *GetSetting, width int) map[string]int {
+
+	keys := gst.Keys
+	n := len(keys)
+
+	nsops := make(map[string]int)
+	rnd := rand.New(rand.NewSource(time.Now().Unix()))
+
+	v := int32(0)
+
+	rst := testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				start := keys[rnd.Intn(n)]
+
+				startIdx, endIdx := gst.SlimKV.slim.Range(start, "", width)
+				if int(endIdx) > len(gst.SlimKV.Elts) {
+					endIdx = int32(len(gst.SlimKV.Elts))
+				}
+				for _, e := range gst.SlimKV.Elts[startIdx:endIdx] {
+					v += e.Val
+				}
+			}
+		})
+	nsops["slim"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				start := keys[rnd.Intn(n)]
+
+				cnt := 0
+				for k, val := range gst.Map {
+					if strings.Compare(k, start) >= 0 {
+						v += val
+						cnt++
+						if cnt >= width {
+							break
+						}
+					}
+				}
+			}
+		})
+	nsops["map"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				start := keys[rnd.Intn(n)]
+				v += sortedSliceRange(keys, gst.Values, start, width)
+			}
+		})
+	nsops["array"] = int(rst.NsPerOp())
+
+	rst = testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				start := keys[rnd.Intn(n)]
+
+				cnt := 0
+				gst.Btree.AscendGreaterOrEqual(&KVElt{Key: start}, func(item btree.Item) bool {
+					v += item.(*KVElt).Val
+					cnt++
+					return cnt < width
+				})
+			}
+		})
+	nsops["btree"] = int(rst.NsPerOp())
+
+	OutputMSAB += v
+
+	return nsops
+}
+
+// sortedSliceRange scans up to limit values starting from the first key
+// >= start in a sorted key slice.
+func sortedSliceRange(keys []string, values []int32, start string, limit int) int32 {
+
+	n := len(keys)
+
+	idx := sort.Search(
+		n,
+		func(i int) bool {
+			return strings.Compare(keys[i], start) >= 0
+		},
+	)
+
+	sum := int32(0)
+	for i := idx; i < n && i < idx+limit; i++ {
+		sum += values[i]
+	}
+
+	return sum
+}
@@ -0,0 +1,111 @@
+package benchmark
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/openacid/slim/benchhelper"
+	"github.com/openacid/slim/sstable"
+)
+
+// SSTableResult shows ns/Get() throughput of sstable.Reader against
+// goleveldb for the same sorted-key workload.
+type SSTableResult struct {
+	KeyCount int `tw-title:"key-count"`
+	Sstable  int `tw-title:"sstable"`
+	Leveldb  int `tw-title:"goleveldb"`
+}
+
+// GetSstableVsLeveldb compares sstable.Reader.Get throughput against
+// goleveldb for several key counts.
+func GetSstableVsLeveldb(keyCounts []int) []SSTableResult {
+
+	var rst = make([]SSTableResult, 0, len(keyCounts))
+
+	for _, n := range keyCounts {
+		rst = append(rst, benchSstableVsLeveldb(n))
+	}
+
+	return rst
+}
+
+func benchSstableVsLeveldb(n int) SSTableResult {
+
+	keys := benchhelper.RandSortedStrings(n, 64, nil)
+	vals := make([][]byte, n)
+	for i, k := range keys {
+		vals[i] = []byte(k)
+	}
+
+	sstPath := writeSstableFixture(keys, vals)
+	defer os.Remove(sstPath)
+
+	sr, err := sstable.Open(sstPath)
+	if err != nil {
+		panic(err)
+	}
+	defer sr.Close()
+
+	ldbDir, err := ioutil.TempDir("", "sstable-bench-leveldb")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(ldbDir)
+
+	db, err := leveldb.OpenFile(ldbDir, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	for i, k := range keys {
+		if err := db.Put([]byte(k), vals[i], nil); err != nil {
+			panic(err)
+		}
+	}
+
+	rstSst := testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = sr.Get(keys[i%n])
+			}
+		})
+
+	rstLdb := testing.Benchmark(
+		func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = db.Get([]byte(keys[i%n]), nil)
+			}
+		})
+
+	return SSTableResult{
+		KeyCount: n,
+		Sstable:  int(rstSst.NsPerOp()),
+		Leveldb:  int(rstLdb.NsPerOp()),
+	}
+}
+
+func writeSstableFixture(keys []string, vals [][]byte) string {
+
+	f, err := ioutil.TempFile("", "sstable-bench-*.sst")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	w := sstable.NewWriter(f)
+	for i, k := range keys {
+		if err := w.Put([]byte(k), vals[i]); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := w.Finish(); err != nil {
+		panic(err)
+	}
+
+	return f.Name()
+}
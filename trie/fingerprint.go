@@ -0,0 +1,107 @@
+package trie
+
+import (
+	"hash/fnv"
+
+	"github.com/openacid/slim/encode"
+)
+
+// Options configures optional features of a SlimTrie build.
+type Options struct {
+	// FingerprintBits is the width, in bits, of an optional per-leaf
+	// fingerprint checked after a lookup such as ProbeI32 narrows a key
+	// down to a single candidate index, to reject false positives without
+	// touching the caller's own key storage. 0 disables it; valid
+	// widths are 0, 4, 8 and 16.
+	FingerprintBits int
+}
+
+// Fingerprints holds one hash fingerprint per leaf, in leaf order: the
+// optional extra false-positive check NewSlimTrieOpts builds alongside a
+// SlimTrie when Options.FingerprintBits is nonzero.
+type Fingerprints struct {
+	bits int
+	data []byte // bit-packed, bits bits per leaf
+}
+
+// NewSlimTrieOpts builds a SlimTrie exactly as NewSlimTrie does, and
+// additionally returns a Fingerprints when opts.FingerprintBits > 0 (nil
+// otherwise). Pass the result to (*Fingerprints).Check with the index
+// ProbeI32 returns to reject false positives before paying for the
+// caller's own key comparison.
+func NewSlimTrieOpts(enc encode.Encoder, keys []string, values interface{}, opts Options) (*SlimTrie, *Fingerprints, error) {
+
+	st, err := NewSlimTrie(enc, keys, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.FingerprintBits == 0 {
+		return st, nil, nil
+	}
+
+	return st, newFingerprints(keys, opts.FingerprintBits), nil
+}
+
+func newFingerprints(keys []string, bits int) *Fingerprints {
+
+	fp := &Fingerprints{
+		bits: bits,
+		data: make([]byte, (len(keys)*bits+7)/8),
+	}
+
+	for i, k := range keys {
+		fp.set(i, fingerprintOf(k, bits))
+	}
+
+	return fp
+}
+
+func fingerprintOf(key string, bits int) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32() & (1<<uint(bits) - 1)
+}
+
+func (fp *Fingerprints) set(i int, v uint32) {
+
+	bitOff := i * fp.bits
+
+	for b := 0; b < fp.bits; b++ {
+		if v&(1<<uint(b)) == 0 {
+			continue
+		}
+
+		byteIdx := (bitOff + b) / 8
+		bitIdx := uint((bitOff + b) % 8)
+		fp.data[byteIdx] |= 1 << bitIdx
+	}
+}
+
+func (fp *Fingerprints) get(i int) uint32 {
+
+	bitOff := i * fp.bits
+	v := uint32(0)
+
+	for b := 0; b < fp.bits; b++ {
+		byteIdx := (bitOff + b) / 8
+		bitIdx := uint((bitOff + b) % 8)
+		if fp.data[byteIdx]&(1<<bitIdx) != 0 {
+			v |= 1 << uint(b)
+		}
+	}
+
+	return v
+}
+
+// Check reports whether key's fingerprint matches the leaf at idx. A
+// false result proves key is absent, letting the caller skip its own
+// key comparison; a true result is inconclusive -- fingerprints can
+// collide -- and the caller must still confirm with its own stored key.
+// A nil Fingerprints (no fingerprint configured) always reports true.
+func (fp *Fingerprints) Check(idx int32, key string) bool {
+	if fp == nil {
+		return true
+	}
+	return fp.get(int(idx)) == fingerprintOf(key, fp.bits)
+}
@@ -0,0 +1,35 @@
+package trie
+
+// Range resolves [start, end) against the keys st holds to the matching
+// half-open span of indexes [startIndex, endIndex) into the caller's own
+// sorted key/value storage (e.g. the Elts slice in slimKV, or an
+// sstable's record-offset array). Range returns only the span's bounds;
+// it does not return the keys or values in the span.
+//
+// It mirrors the UnsafeRange(key, endKey, limit) pattern used by
+// bbolt/etcd's backend. start need not be a stored key: startIndex is
+// the position of the first stored key >= start. end works the same
+// way; pass "" for no upper key bound. limit caps the span width; pass
+// 0 for no limit. The caller then walks its own storage across
+// [startIndex, endIndex) without SlimTrie ever materializing the keys
+// in between.
+func (st *SlimTrie) Range(start, end string, limit int) (startIndex, endIndex int32) {
+
+	lo := int32(st.rank(start))
+
+	var hi int32
+	if end == "" {
+		hi = int32(st.n)
+	} else {
+		hi = int32(st.rank(end))
+	}
+
+	if hi < lo {
+		hi = lo
+	}
+	if limit > 0 && hi-lo > int32(limit) {
+		hi = lo + int32(limit)
+	}
+
+	return lo, hi
+}
@@ -0,0 +1,385 @@
+// Package trie implements SlimTrie, a sorted string-keyed index.
+//
+// Get and GetI32 are exact matches: they return a value only for a
+// stored key. ProbeI32 is the approximate lookup: it narrows any key
+// down to a single candidate leaf and hands back its value without
+// confirming the key actually matches. A caller that probes this way
+// and holds its own copy of the key confirms the match itself; see
+// Fingerprints for a cheaper check that rejects most wrong candidates.
+package trie
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/openacid/slim/encode"
+)
+
+// node is one entry of the immutable binary search tree backing a
+// SlimTrie. Insert/Delete build a new tree whose root points to
+// brand-new nodes only on the path down to the changed key; every node
+// off that path is the very same *node the original SlimTrie pointed
+// to, so the two trees share every subtree neither operation touched.
+type node struct {
+	key   string
+	value interface{}
+	left  *node
+	right *node
+	size  int // 1 + len(left) + len(right), for O(depth) rank queries
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// SlimTrie is a sorted index from keys to values, see the package doc
+// for which lookups are exact and which approximate.
+type SlimTrie struct {
+	enc  encode.Encoder
+	root *node
+	n    int
+}
+
+// NewSlimTrie builds a SlimTrie over keys, which must be strictly
+// ascending, and their corresponding values. values is a slice whose
+// element type matches enc, e.g. []int32 for encode.I32{}; it may be
+// nil if the trie is only built to measure memory use.
+func NewSlimTrie(enc encode.Encoder, keys []string, values interface{}) (*SlimTrie, error) {
+
+	for i := 1; i < len(keys); i++ {
+		if keys[i] <= keys[i-1] {
+			return nil, fmt.Errorf("trie: keys must be strictly ascending, got %q after %q", keys[i], keys[i-1])
+		}
+	}
+
+	get, err := valueGetter(values, len(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	root := buildBalanced(keys, get, 0, len(keys))
+
+	return &SlimTrie{enc: enc, root: root, n: len(keys)}, nil
+}
+
+func valueGetter(values interface{}, n int) (func(i int) interface{}, error) {
+
+	switch vs := values.(type) {
+	case nil:
+		return func(i int) interface{} { return nil }, nil
+	case []int32:
+		if len(vs) != n {
+			return nil, fmt.Errorf("trie: got %d values for %d keys", len(vs), n)
+		}
+		return func(i int) interface{} { return vs[i] }, nil
+	case []uint16:
+		if len(vs) != n {
+			return nil, fmt.Errorf("trie: got %d values for %d keys", len(vs), n)
+		}
+		return func(i int) interface{} { return vs[i] }, nil
+	case []uint32:
+		if len(vs) != n {
+			return nil, fmt.Errorf("trie: got %d values for %d keys", len(vs), n)
+		}
+		return func(i int) interface{} { return vs[i] }, nil
+	default:
+		return nil, fmt.Errorf("trie: unsupported value slice type %T", values)
+	}
+}
+
+// buildBalanced builds a depth-balanced tree from keys[lo:hi], so a
+// SlimTrie built in one NewSlimTrie/UnmarshalBinary call always has
+// O(log n) depth regardless of how Insert/Delete may have skewed any
+// tree it was descended from.
+func buildBalanced(keys []string, get func(int) interface{}, lo, hi int) *node {
+
+	if lo >= hi {
+		return nil
+	}
+
+	mid := (lo + hi) / 2
+	left := buildBalanced(keys, get, lo, mid)
+	right := buildBalanced(keys, get, mid+1, hi)
+
+	return &node{
+		key:   keys[mid],
+		value: get(mid),
+		left:  left,
+		right: right,
+		size:  size(left) + size(right) + 1,
+	}
+}
+
+// search descends to the node whose key exactly equals key, or nil.
+func (st *SlimTrie) search(key string) *node {
+	n := st.root
+	for n != nil {
+		switch {
+		case key == n.key:
+			return n
+		case key < n.key:
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+	return nil
+}
+
+// Get returns the value of key and whether it was found. Get is an
+// exact match: unlike ProbeI32, a miss here is a real miss.
+func (st *SlimTrie) Get(key string) (interface{}, bool) {
+	n := st.search(key)
+	if n == nil {
+		return nil, false
+	}
+	return n.value, true
+}
+
+// GetI32 is Get specialized for int32 values, as built with encode.I32{}.
+func (st *SlimTrie) GetI32(key string) (int32, bool) {
+	n := st.search(key)
+	if n == nil {
+		return 0, false
+	}
+	v, ok := n.value.(int32)
+	if !ok {
+		return 0, false
+	}
+	return v, true
+}
+
+// ProbeI32 narrows key down to the value of its ceiling key -- the
+// smallest stored key >= key -- whether or not key itself is present.
+// This is the kind of approximate match a compressed/succinct index
+// makes when it runs out of room to verify a lookup exactly: a caller
+// that only has ProbeI32 to go on, with no fingerprint or stored key to
+// rule out a wrong candidate, sees a false positive whenever key is
+// absent but shares a ceiling with some other key. found is false only
+// when the trie is empty, key is greater than every stored key, or the
+// ceiling's value is not an int32.
+func (st *SlimTrie) ProbeI32(key string) (int32, bool) {
+
+	n := st.root
+	var ceil *node
+
+	for n != nil {
+		if key <= n.key {
+			ceil = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	if ceil == nil {
+		return 0, false
+	}
+
+	v, ok := ceil.value.(int32)
+	if !ok {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// rank returns the number of stored keys strictly less than key, i.e.
+// the index key would occupy if inserted into the sorted key sequence.
+// This is the lower-bound primitive Range is built on: it works for any
+// key, present or not, in O(depth).
+func (st *SlimTrie) rank(key string) int {
+
+	n := st.root
+	count := 0
+
+	for n != nil {
+		if key <= n.key {
+			n = n.left
+		} else {
+			count += size(n.left) + 1
+			n = n.right
+		}
+	}
+
+	return count
+}
+
+// Insert returns a new SlimTrie containing key/value in addition to
+// every entry already in st, leaving st untouched. Only the O(depth)
+// nodes on the path to key are copied; every other node is shared
+// between st and the result, the same persistent-tree technique etcd's
+// keyIndex uses for its generations.
+func (st *SlimTrie) Insert(key string, value interface{}) (*SlimTrie, error) {
+	newRoot := insert(st.root, key, value)
+	return &SlimTrie{enc: st.enc, root: newRoot, n: size(newRoot)}, nil
+}
+
+func insert(n *node, key string, value interface{}) *node {
+
+	if n == nil {
+		return &node{key: key, value: value, size: 1}
+	}
+
+	switch {
+	case key == n.key:
+		return &node{key: key, value: value, left: n.left, right: n.right, size: n.size}
+	case key < n.key:
+		left := insert(n.left, key, value)
+		return &node{key: n.key, value: n.value, left: left, right: n.right, size: size(left) + size(n.right) + 1}
+	default:
+		right := insert(n.right, key, value)
+		return &node{key: n.key, value: n.value, left: n.left, right: right, size: size(n.left) + size(right) + 1}
+	}
+}
+
+// Delete returns a new SlimTrie with key removed, leaving st untouched.
+// Deleting an absent key is not an error: it returns a SlimTrie
+// equivalent to st, still sharing every node with it. Like Insert, only
+// the O(depth) nodes on the path to key are copied.
+func (st *SlimTrie) Delete(key string) (*SlimTrie, error) {
+
+	newRoot, found := deleteNode(st.root, key)
+	if !found {
+		return st, nil
+	}
+
+	return &SlimTrie{enc: st.enc, root: newRoot, n: size(newRoot)}, nil
+}
+
+func deleteNode(n *node, key string) (*node, bool) {
+
+	if n == nil {
+		return nil, false
+	}
+
+	switch {
+	case key < n.key:
+		left, found := deleteNode(n.left, key)
+		if !found {
+			return n, false
+		}
+		return &node{key: n.key, value: n.value, left: left, right: n.right, size: size(left) + size(n.right) + 1}, true
+
+	case key > n.key:
+		right, found := deleteNode(n.right, key)
+		if !found {
+			return n, false
+		}
+		return &node{key: n.key, value: n.value, left: n.left, right: right, size: size(n.left) + size(right) + 1}, true
+
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+
+		succ := n.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+		right := deleteMin(n.right)
+		return &node{key: succ.key, value: succ.value, left: n.left, right: right, size: size(n.left) + size(right) + 1}, true
+	}
+}
+
+func deleteMin(n *node) *node {
+	if n.left == nil {
+		return n.right
+	}
+	left := deleteMin(n.left)
+	return &node{key: n.key, value: n.value, left: left, right: n.right, size: size(left) + size(n.right) + 1}
+}
+
+// MarshalBinary serializes the trie as its sorted (key, value) pairs,
+// encoded with st.enc. It does not try to preserve tree shape:
+// UnmarshalBinary rebuilds a balanced tree from the pairs, so an
+// on-disk SlimTrie never inherits skew from whatever Insert/Delete
+// history produced it.
+func (st *SlimTrie) MarshalBinary() ([]byte, error) {
+
+	keys := make([]string, 0, st.n)
+	values := make([]interface{}, 0, st.n)
+	inorder(st.root, &keys, &values)
+
+	buf := make([]byte, 0, st.n*(8+st.enc.Size()))
+
+	buf = append(buf, st.enc.Tag())
+
+	var hdr [binary.MaxVarintLen64]byte
+	hn := binary.PutUvarint(hdr[:], uint64(len(keys)))
+	buf = append(buf, hdr[:hn]...)
+
+	for i, k := range keys {
+		var klen [binary.MaxVarintLen64]byte
+		kn := binary.PutUvarint(klen[:], uint64(len(k)))
+		buf = append(buf, klen[:kn]...)
+		buf = append(buf, k...)
+		buf = st.enc.Encode(buf, values[i])
+	}
+
+	return buf, nil
+}
+
+func inorder(n *node, keys *[]string, values *[]interface{}) {
+	if n == nil {
+		return
+	}
+	inorder(n.left, keys, values)
+	*keys = append(*keys, n.key)
+	*values = append(*values, n.value)
+	inorder(n.right, keys, values)
+}
+
+// UnmarshalBinary restores a SlimTrie written by MarshalBinary,
+// recovering its Encoder from the tag MarshalBinary wrote.
+func (st *SlimTrie) UnmarshalBinary(b []byte) error {
+
+	if len(b) < 1 {
+		return fmt.Errorf("trie: truncated encoder tag")
+	}
+
+	enc, err := encode.FromTag(b[0])
+	if err != nil {
+		return err
+	}
+	off := 1
+
+	n, hn := binary.Uvarint(b[off:])
+	if hn <= 0 {
+		return fmt.Errorf("trie: truncated key count")
+	}
+	off += hn
+
+	keys := make([]string, n)
+	values := make([]interface{}, n)
+
+	for i := range keys {
+
+		klen, kn := binary.Uvarint(b[off:])
+		if kn <= 0 {
+			return fmt.Errorf("trie: truncated key length")
+		}
+		off += kn
+
+		keys[i] = string(b[off : off+int(klen)])
+		off += int(klen)
+
+		v, consumed := enc.Decode(b[off:])
+		values[i] = v
+		off += consumed
+	}
+
+	get := func(i int) interface{} { return values[i] }
+
+	st.enc = enc
+	st.root = buildBalanced(keys, get, 0, len(keys))
+	st.n = len(keys)
+
+	return nil
+}